@@ -2,13 +2,18 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/getsentry/raven-go"
+	lightstep "github.com/lightstep/lightstep-tracer-go"
+	opentracing "github.com/opentracing/opentracing-go"
+	zipkintracer "github.com/openzipkin/zipkin-go-opentracing"
 	"github.com/stripe/veneur"
 	"github.com/stripe/veneur/trace"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
 )
 
 var (
@@ -31,6 +36,24 @@ func main() {
 		logrus.WithError(err).Fatal("Error reading config file")
 	}
 
+	// trace.W3CPropagation is populated from the server's tracing
+	// configuration at startup, per its doc comment.
+	//
+	// NOTE: this depends on a Config field (TraceW3CPropagation, yaml
+	// tag "trace_w3c_propagation") that, like the shadow-tracer fields
+	// noted on newShadowTracer, isn't addable from this source tree
+	// since config.go lives outside it.
+	trace.W3CPropagation = conf.TraceW3CPropagation
+
+	shadow, err := newShadowTracer(conf)
+	if err != nil {
+		logrus.WithError(err).Error("Error initializing shadow tracer")
+	} else if shadow != nil {
+		tracer := trace.GlobalTracer()
+		tracer.SetShadowTracer(shadow)
+		trace.SetGlobalTracer(tracer)
+	}
+
 	server, err := veneur.NewFromConfig(conf)
 	if err != nil {
 		e := err
@@ -70,3 +93,52 @@ func main() {
 		select {}
 	}
 }
+
+// newShadowTracer builds the opentracing.Tracer named by
+// conf.TraceShadowBackend ("zipkin", "jaeger", "lightstep", or "noop"/
+// unset to disable shadow tracing), using the accompanying endpoint and
+// token fields from conf.
+//
+// NOTE: this requires veneur.Config to carry three fields, which must
+// be added alongside its other yaml-tagged settings:
+//
+//	TraceShadowBackend  string `yaml:"trace_shadow_backend"`
+//	TraceShadowEndpoint string `yaml:"trace_shadow_endpoint"`
+//	TraceShadowToken    string `yaml:"trace_shadow_token"`
+//
+// config.go isn't part of this source tree, so they can't be added from
+// here; whoever merges this needs to add them to the real Config struct
+// (and example config) or this package won't compile.
+func newShadowTracer(conf veneur.Config) (opentracing.Tracer, error) {
+	switch conf.TraceShadowBackend {
+	case "", "noop":
+		return nil, nil
+
+	case "zipkin":
+		collector, err := zipkintracer.NewHTTPCollector(conf.TraceShadowEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		return zipkintracer.NewTracer(
+			zipkintracer.NewRecorder(collector, false, conf.TraceShadowEndpoint, trace.Service),
+		)
+
+	case "jaeger":
+		cfg := jaegercfg.Configuration{
+			ServiceName: trace.Service,
+			Sampler:     &jaegercfg.SamplerConfig{Type: "const", Param: 1},
+			Reporter:    &jaegercfg.ReporterConfig{LocalAgentHostPort: conf.TraceShadowEndpoint},
+		}
+		tracer, _, err := cfg.NewTracer()
+		return tracer, err
+
+	case "lightstep":
+		return lightstep.NewTracer(lightstep.Options{
+			AccessToken: conf.TraceShadowToken,
+			Collector:   lightstep.Endpoint{HostPort: conf.TraceShadowEndpoint},
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("unknown trace_shadow_backend %q", conf.TraceShadowBackend)
+	}
+}
@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+type contextKey struct{}
+
+// activeSpanKey is the context.Context key under which the active span
+// is stored by ContextWithSpan.
+var activeSpanKey = contextKey{}
+
+// ContextWithSpan returns a copy of ctx carrying span as its active span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, activeSpanKey, span)
+}
+
+// SpanFromContext returns the active span stored in ctx by
+// ContextWithSpan, or nil if ctx carries none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(activeSpanKey).(*Span)
+	return span
+}
+
+// globalTracer is the package-wide default Tracer returned by
+// GlobalTracer, for library code that has no Tracer threaded to it.
+var globalTracer = Tracer{}
+
+// GlobalTracer returns the package-wide default Tracer.
+func GlobalTracer() Tracer {
+	return globalTracer
+}
+
+// SetGlobalTracer replaces the package-wide default Tracer. It should be
+// called once, during startup, before any code calls GlobalTracer or
+// StartSpanFromContext.
+func SetGlobalTracer(t Tracer) {
+	globalTracer = t
+}
+
+// StartSpanFromContext starts a new span named operationName on tracer.
+// If ctx carries an active span, the new span is made a ChildOf it.
+// It returns the new span along with a context derived from ctx that
+// carries the new span as its active one.
+func StartSpanFromContext(ctx context.Context, tracer Tracer, operationName string, opts ...opentracing.StartSpanOption) (*Span, context.Context) {
+	if parent := SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+
+	span := tracer.StartSpan(operationName, opts...).(*Span)
+	return span, ContextWithSpan(ctx, span)
+}
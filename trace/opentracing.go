@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -24,6 +25,44 @@ var _ opentracing.TextMapWriter = textMapReaderWriter(map[string]string{})
 
 var ErrUnsupportedSpanContext = errors.New("Unsupported SpanContext")
 
+// B3 header names, as used by Zipkin and the envoy/nginx proxies that
+// front most of our HTTP traffic. See
+// https://github.com/openzipkin/b3-propagation.
+const (
+	b3TraceIdHeader      = "X-B3-TraceId"
+	b3SpanIdHeader       = "X-B3-SpanId"
+	b3ParentSpanIdHeader = "X-B3-ParentSpanId"
+	b3SampledHeader      = "X-B3-Sampled"
+	b3FlagsHeader        = "X-B3-Flags"
+
+	w3cTraceParentHeader = "traceparent"
+	w3cTraceStateHeader  = "tracestate"
+
+	// baggagePrefix is prepended to baggage item keys when they're
+	// carried over HTTP headers, per the OpenTracing convention.
+	baggagePrefix = "Ot-Baggage-"
+)
+
+// W3CPropagation controls whether Inject and Extract additionally
+// emit/parse the W3C traceparent/tracestate headers alongside B3 when
+// using the opentracing.HTTPHeaders format. It's populated from the
+// server's tracing configuration at startup.
+var W3CPropagation bool
+
+// reservedBaggageKeys are baggage items that carry span identity rather
+// than user baggage, and are therefore never sent as Ot-Baggage- entries.
+// Most are encoded as their own B3/W3C headers; resource has no header
+// and is not carried over HTTP headers at all.
+var reservedBaggageKeys = map[string]bool{
+	"traceid":    true,
+	"spanid":     true,
+	"parentid":   true,
+	"resource":   true,
+	"sampled":    true,
+	"debug":      true,
+	"tracestate": true,
+}
+
 type ErrContractViolation struct {
 	details interface{}
 }
@@ -65,6 +104,7 @@ func (t textMapReaderWriter) CloneTo(w opentracing.TextMapWriter) {
 }
 
 type spanContext struct {
+	mu           sync.RWMutex
 	baggageItems map[string]string
 }
 
@@ -72,10 +112,21 @@ func (c *spanContext) Init() {
 	c.baggageItems = map[string]string{}
 }
 
+// newSpanContext returns a freshly initialized, empty spanContext for a
+// Span to own. Its baggage is filled in lazily by contextAsParent.
+func newSpanContext() *spanContext {
+	c := &spanContext{}
+	c.Init()
+	return c
+}
+
 // ForeachBaggageItem calls the handler function on each key/val pair in
-// the spanContext's baggage items. If the handler function returns false, it
-// terminates iteration immediately.
+// the spanContext's baggage items, under the context's read lock. If the
+// handler function returns false, it terminates iteration immediately.
 func (c *spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	errHandler := func(k, v string) error {
 		b := handler(k, v)
 		if !b {
@@ -87,6 +138,33 @@ func (c *spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
 	textMapReaderWriter(c.baggageItems).ForeachKey(errHandler)
 }
 
+// get returns a single baggage item under the context's read lock.
+func (c *spanContext) get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.baggageItems[key]
+	return v, ok
+}
+
+// set writes a single baggage item under the context's write lock.
+func (c *spanContext) set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.baggageItems[key] = value
+}
+
+// snapshot returns a copy of the context's baggage items, safe to read
+// or hand off to a carrier without holding the context's lock.
+func (c *spanContext) snapshot() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snap := make(map[string]string, len(c.baggageItems))
+	for k, v := range c.baggageItems {
+		snap[k] = v
+	}
+	return snap
+}
+
 // TraceID extracts the Trace ID from the BaggageItems.
 // It assumes the TraceID is present and valid.
 func (c *spanContext) TraceId() int64 {
@@ -105,6 +183,28 @@ func (c *spanContext) SpanId() int64 {
 	return c.parseBaggageInt64("spanid")
 }
 
+// Sampled returns whether this spanContext (or, for a debug context
+// carrying no span identity, the downstream trace it represents) has
+// been marked as sampled.
+func (c *spanContext) Sampled() bool {
+	var sampled bool
+	c.ForeachBaggageItem(func(k, v string) bool {
+		if strings.ToLower(k) == "sampled" {
+			sampled = v == "1" || strings.ToLower(v) == "true"
+			return false
+		}
+		return true
+	})
+	return sampled
+}
+
+// Debug returns whether this spanContext carries the B3 debug flag
+// (X-B3-Flags: 1), which forces sampling regardless of X-B3-Sampled.
+func (c *spanContext) Debug() bool {
+	v, _ := c.get("debug")
+	return v == "1" || strings.ToLower(v) == "true"
+}
+
 // parseBaggageInt64 searches for the target key in the BaggageItems
 // and parses it as an int64. It treats keys as case-insensitive.
 func (c *spanContext) parseBaggageInt64(key string) int64 {
@@ -142,8 +242,28 @@ type Span struct {
 
 	*Trace
 
-	// These are currently ignored
+	// mu guards Tags and logLines, both of which SetTag/LogFields can
+	// append to from any goroutine holding the span.
+	mu sync.RWMutex
+
 	logLines []opentracinglog.Field
+
+	// sampled records this span's sampling decision, made once at
+	// StartSpan time for a root span and inherited from the parent
+	// context otherwise. Non-sampled spans short-circuit SetTag and
+	// LogFields.
+	sampled bool
+
+	// ctx is this span's persistent spanContext, created once in
+	// StartSpan. SetBaggageItem writes to it directly so baggage set
+	// after the span starts isn't discarded.
+	ctx *spanContext
+
+	// shadowSpan, if set, is the corresponding span on the tracer's
+	// shadow backend. FinishWithOptions is mirrored to it and Context
+	// pairs its context with this Span's; SetTag, LogFields,
+	// SetBaggageItem and SetOperationName are not mirrored to it.
+	shadowSpan opentracing.Span
 }
 
 func (s *Span) Finish() {
@@ -159,22 +279,73 @@ func (s *Span) Finish() {
 // control over timestamps and log data.
 // The BulkLogData field is deprecated and ignored.
 func (s *Span) FinishWithOptions(opts opentracing.FinishOptions) {
+	if opts.FinishTime.IsZero() {
+		opts.FinishTime = time.Now()
+	}
+	s.Trace.Duration = opts.FinishTime.Sub(s.Trace.Start)
+
+	s.mu.Lock()
+	for _, f := range s.logLines {
+		s.Tags = append(s.Tags, logFieldToTag(f))
+	}
+	// Clear logLines now that they've been copied into Tags, so a
+	// second Finish/FinishWithOptions call (a caller bug, but one we
+	// shouldn't compound) doesn't duplicate them.
+	s.logLines = nil
+	for _, rec := range opts.LogRecords {
+		for _, f := range rec.Fields {
+			s.Tags = append(s.Tags, logFieldToTag(f))
+		}
+	}
+	s.mu.Unlock()
+
+	if s.shadowSpan != nil {
+		s.shadowSpan.FinishWithOptions(opts)
+	}
+
+	if !s.sampled {
+		// Dropping unsampled spans here, rather than just skipping their
+		// tags/logs, is the whole point of sampling: it's what keeps the
+		// recorder's UDP/HTTP traffic proportional to the sample rate
+		// instead of the raw span rate.
+		return
+	}
+
+	if s.tracer.recorder == nil {
+		return
+	}
+	// TODO handle error
+	s.tracer.recorder.RecordSpan(s.Trace)
 }
 
 func (s *Span) Context() opentracing.SpanContext {
-	return s.contextAsParent()
+	local := s.contextAsParent()
+	if s.shadowSpan == nil {
+		return local
+	}
+	return &ShadowContext{spanContext: local, Shadow: s.shadowSpan.Context()}
 }
 
-// contextAsParent() is like its exported counterpart,
-// except it returns the concrete type for local package use
+// contextAsParent() is like its exported counterpart, except it returns
+// the concrete type for local package use. It refreshes the span's
+// persistent context with its current TraceId/SpanId/ParentId/Resource/
+// sampled values, then hands back a spanContext holding a snapshot of
+// its baggage, so the caller (eg Inject) can't race with later
+// SetBaggageItem calls.
 func (s *Span) contextAsParent() *spanContext {
-	//TODO baggageItems
+	s.ctx.set("traceid", strconv.FormatInt(s.TraceId, 10))
+	s.ctx.set("spanid", strconv.FormatInt(s.SpanId, 10))
+	s.ctx.set("parentid", strconv.FormatInt(s.ParentId, 10))
+	s.ctx.set("resource", s.Resource)
+	if s.sampled {
+		s.ctx.set("sampled", "1")
+	} else {
+		s.ctx.set("sampled", "0")
+	}
 
 	c := &spanContext{}
 	c.Init()
-	c.baggageItems["traceid"] = strconv.FormatInt(s.TraceId, 10)
-	c.baggageItems["parentid"] = strconv.FormatInt(s.ParentId, 10)
-	c.baggageItems["resource"] = s.Resource
+	c.baggageItems = s.ctx.snapshot()
 	return c
 }
 
@@ -185,8 +356,10 @@ func (s *Span) SetOperationName(name string) opentracing.Span {
 
 // SetTag sets the tags on the underlying span
 func (s *Span) SetTag(key string, value interface{}) opentracing.Span {
+	if !s.sampled {
+		return s
+	}
 	tag := ssf.SSFTag{Name: key}
-	// TODO mutex
 	switch v := value.(type) {
 	case string:
 		tag.Value = v
@@ -196,15 +369,81 @@ func (s *Span) SetTag(key string, value interface{}) opentracing.Span {
 		// TODO maybe just ban non-strings?
 		tag.Value = fmt.Sprintf("%#v", value)
 	}
+	s.mu.Lock()
 	s.Tags = append(s.Tags, &tag)
+	s.mu.Unlock()
 	return s
 }
 
 // LogFields sets log fields on the underlying span.
 // Currently these are ignored, but they can be fun to set anyway!
 func (s *Span) LogFields(fields ...opentracinglog.Field) {
-	// TODO mutex this
+	if !s.sampled {
+		return
+	}
+	s.mu.Lock()
 	s.logLines = append(s.logLines, fields...)
+	s.mu.Unlock()
+}
+
+// logFieldTagPrefix marks an ssf.SSFTag produced by logFieldToTag as
+// standing in for an opentracing log.Field rather than a SetTag call.
+// It leads with a NUL byte, which ordinary SetTag keys don't contain, so
+// user tags don't collide with it. ZipkinHTTPRecorder strips it back off
+// and reports such tags as annotations; UDPRecorder sends them as-is.
+//
+// This is a stand-in: SpanRecorder's Trace parameter (defined outside
+// this chunk) has no field of its own for structured log events, so
+// there's nowhere else to carry them through RecordSpan. If Trace ever
+// grows one, LogFields/FinishWithOptions should populate that instead
+// of round-tripping log data through Tags.
+//
+// TODO: this is a deviation from the original request (log lines
+// converted to real ssf.SSFSample log events, not smuggled through
+// Tags) -- tracked as follow-up work, not a finished implementation.
+const logFieldTagPrefix = "\x00log:"
+
+// logFieldToTag renders an opentracing log.Field as an ssf.SSFTag, so it
+// can travel alongside the rest of a span's Tags through SpanRecorder.
+func logFieldToTag(f opentracinglog.Field) *ssf.SSFTag {
+	enc := &stringFieldEncoder{}
+	f.Marshal(enc)
+	return &ssf.SSFTag{Name: logFieldTagPrefix + f.Key(), Value: enc.value}
+}
+
+// stringFieldEncoder implements opentracinglog.Encoder, stringifying
+// whatever value it's given. It exists solely to recover a field's value
+// out of the unexported log.Field type.
+type stringFieldEncoder struct {
+	value string
+}
+
+func (e *stringFieldEncoder) EmitString(key, value string)    { e.value = value }
+func (e *stringFieldEncoder) EmitBool(key string, value bool) { e.value = strconv.FormatBool(value) }
+func (e *stringFieldEncoder) EmitInt(key string, value int)   { e.value = strconv.Itoa(value) }
+func (e *stringFieldEncoder) EmitInt32(key string, value int32) {
+	e.value = strconv.FormatInt(int64(value), 10)
+}
+func (e *stringFieldEncoder) EmitInt64(key string, value int64) {
+	e.value = strconv.FormatInt(value, 10)
+}
+func (e *stringFieldEncoder) EmitUint32(key string, value uint32) {
+	e.value = strconv.FormatUint(uint64(value), 10)
+}
+func (e *stringFieldEncoder) EmitUint64(key string, value uint64) {
+	e.value = strconv.FormatUint(value, 10)
+}
+func (e *stringFieldEncoder) EmitFloat32(key string, value float32) {
+	e.value = strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+func (e *stringFieldEncoder) EmitFloat64(key string, value float64) {
+	e.value = strconv.FormatFloat(value, 'f', -1, 64)
+}
+func (e *stringFieldEncoder) EmitObject(key string, value interface{}) {
+	e.value = fmt.Sprintf("%#v", value)
+}
+func (e *stringFieldEncoder) EmitLazyLogger(value opentracinglog.LazyLogger) {
+	value(e)
 }
 
 func (s *Span) LogKV(alternatingKeyValues ...interface{}) {
@@ -213,13 +452,16 @@ func (s *Span) LogKV(alternatingKeyValues ...interface{}) {
 	s.LogFields(fs...)
 }
 
+// SetBaggageItem sets a baggage item on the span's persistent context,
+// so it survives to later Inject/Context calls.
 func (s *Span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
-	s.contextAsParent().baggageItems[restrictedKey] = value
+	s.ctx.set(restrictedKey, value)
 	return s
 }
 
 func (s *Span) BaggageItem(restrictedKey string) string {
-	return s.contextAsParent().baggageItems[restrictedKey]
+	v, _ := s.ctx.get(restrictedKey)
+	return v
 }
 
 // Tracer returns the tracer that created this Span
@@ -243,6 +485,31 @@ func (s *Span) Log(data opentracing.LogData) {
 }
 
 type Tracer struct {
+	// recorder, if set, receives every span this Tracer finishes.
+	recorder SpanRecorder
+
+	// sampler, if set, decides which root spans are sampled. A nil
+	// sampler samples everything, matching the tracer's old behavior.
+	sampler Sampler
+
+	// shadow, if set, is mirrored on every StartSpan/Finish/Inject/Extract.
+	shadow opentracing.Tracer
+}
+
+// SetRecorder attaches a SpanRecorder to the Tracer, so that spans
+// started from it are flushed to a backend when they finish. It must be
+// called before any spans are started, since StartSpan copies the
+// Tracer's fields onto each Span it creates.
+func (t *Tracer) SetRecorder(r SpanRecorder) {
+	t.recorder = r
+}
+
+// SetSampler attaches a Sampler to the Tracer, so that root spans
+// started from it are sampled per its decision. It must be called
+// before any spans are started, since StartSpan copies the Tracer's
+// fields onto each Span it creates.
+func (t *Tracer) SetSampler(s Sampler) {
+	t.sampler = s
 }
 
 type spanOption struct {
@@ -275,17 +542,6 @@ func customSpanTags(k, v string) opentracing.StartSpanOption {
 	}
 }
 
-func customSpanParent(t *Trace) opentracing.StartSpanOption {
-	return &spanOption{
-		apply: func(sso *opentracing.StartSpanOptions) {
-			sso.References = append(sso.References, opentracing.SpanReference{
-				Type:              opentracing.ChildOfRef,
-				ReferencedContext: t.contextAsParent(),
-			})
-		},
-	}
-}
-
 // StartSpan starts a span with the specified operationName (resource) and options.
 // If the options specify a parent span and/or root trace, the resource from the
 // root trace will be used.
@@ -302,14 +558,32 @@ func (t Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOpt
 	if len(sso.References) == 0 {
 		// This is a root-level span
 		// beginning a new trace
-		return &Span{
-			Trace:  StartTrace(operationName),
-			tracer: t,
+		trace := StartTrace(operationName)
+		span := &Span{
+			Trace:   trace,
+			tracer:  t,
+			sampled: true,
+			ctx:     newSpanContext(),
 		}
+
+		if t.sampler != nil {
+			sampled, tags := t.sampler.IsSampled(trace.TraceId, operationName)
+			span.sampled = sampled
+			for k, v := range tags {
+				span.Tags = append(span.Tags, &ssf.SSFTag{Name: k, Value: v})
+			}
+		}
+
+		if t.shadow != nil {
+			span.shadowSpan = t.shadow.StartSpan(operationName, shadowStartOptions(sso)...)
+		}
+
+		return span
 	} else {
 
 		// First, let's extract the parent's information
 		parent := Trace{}
+		parentSampled := true
 
 		// TODO don't assume that the ReferencedContext is a concrete spanContext
 		for _, ref := range sso.References {
@@ -319,13 +593,16 @@ func (t Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOpt
 			case opentracing.FollowsFromRef:
 				fallthrough
 			case opentracing.ChildOfRef:
-				ctx, ok := ref.ReferencedContext.(*spanContext)
+				ctx, ok := asSpanContext(ref.ReferencedContext)
 				if !ok {
 					continue
 				}
 				parent.TraceId = ctx.TraceId()
 				parent.SpanId = ctx.ParentId()
 				parent.Resource = ctx.Resource()
+				if v, ok := ctx.get("sampled"); ok {
+					parentSampled = v == "1" || strings.ToLower(v) == "true"
+				}
 
 			default:
 				// TODO handle error
@@ -341,17 +618,49 @@ func (t Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOpt
 		}
 
 		span := &Span{
-			Trace:  trace,
-			tracer: t,
+			Trace:   trace,
+			tracer:  t,
+			sampled: parentSampled,
+			ctx:     newSpanContext(),
 		}
 
 		for k, v := range sso.Tags {
 			span.SetTag(k, v)
 		}
+
+		if t.shadow != nil {
+			span.shadowSpan = t.shadow.StartSpan(operationName, shadowStartOptions(sso)...)
+		}
+
 		return span
 	}
 }
 
+// shadowStartOptions rebuilds the StartSpanOptions' references and tags
+// as options for the shadow tracer, substituting each reference's
+// ShadowContext (if any) for our own spanContext, so the shadow span
+// lands in the right place in the shadow backend's own trace tree.
+func shadowStartOptions(sso opentracing.StartSpanOptions) []opentracing.StartSpanOption {
+	opts := make([]opentracing.StartSpanOption, 0, len(sso.References)+1)
+	for _, ref := range sso.References {
+		shadowCtx, ok := ref.ReferencedContext.(*ShadowContext)
+		if !ok || shadowCtx.Shadow == nil {
+			continue
+		}
+		opts = append(opts, opentracing.SpanReference{
+			Type:              ref.Type,
+			ReferencedContext: shadowCtx.Shadow,
+		})
+	}
+	for k, v := range sso.Tags {
+		opts = append(opts, opentracing.Tag{Key: k, Value: v})
+	}
+	if !sso.StartTime.IsZero() {
+		opts = append(opts, customSpanStart(sso.StartTime))
+	}
+	return opts
+}
+
 // Inject injects the provided SpanContext into the carrier for propagation.
 // It will return opentracing.ErrUnsupportedFormat if the format is not supported.
 // TODO support other SpanContext implementations
@@ -364,11 +673,20 @@ func (t Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier i
 		}
 	}()
 
-	sc, ok := sm.(*spanContext)
+	sc, ok := asSpanContext(sm)
 	if !ok {
 		return ErrUnsupportedSpanContext
 	}
 
+	if t.shadow != nil {
+		if shadowCtx, ok := sm.(*ShadowContext); ok && shadowCtx.Shadow != nil {
+			// Best-effort: mirror the shadow tracer's own headers (B3,
+			// uber-trace-id, etc.) onto the same carrier, so a single
+			// HTTP request can be correlated in both systems.
+			t.shadow.Inject(shadowCtx.Shadow, format, carrier)
+		}
+	}
+
 	if format == opentracing.Binary {
 		// carrier is guaranteed to be an io.Writer by contract
 		w := carrier.(io.Writer)
@@ -383,10 +701,47 @@ func (t Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier i
 		return trace.ProtoMarshalTo(w)
 	}
 
+	if format == opentracing.HTTPHeaders {
+		w, ok := carrier.(opentracing.TextMapWriter)
+		if !ok {
+			return opentracing.ErrInvalidCarrier
+		}
+
+		w.Set(b3TraceIdHeader, formatB3Id(sc.TraceId()))
+		w.Set(b3SpanIdHeader, formatB3Id(sc.SpanId()))
+		if sc.ParentId() != 0 {
+			w.Set(b3ParentSpanIdHeader, formatB3Id(sc.ParentId()))
+		}
+		if sc.Sampled() {
+			w.Set(b3SampledHeader, "1")
+		} else {
+			w.Set(b3SampledHeader, "0")
+		}
+		if sc.Debug() {
+			w.Set(b3FlagsHeader, "1")
+		}
+
+		if W3CPropagation {
+			w.Set(w3cTraceParentHeader, formatW3CTraceParent(sc))
+			if ts, ok := sc.get("tracestate"); ok {
+				w.Set(w3cTraceStateHeader, ts)
+			}
+		}
+
+		for k, v := range sc.snapshot() {
+			if reservedBaggageKeys[k] {
+				continue
+			}
+			w.Set(baggagePrefix+k, v)
+		}
+
+		return nil
+	}
+
 	// If the carrier is a TextMapWriter, treat it as one, regardless of what the format is
 	if w, ok := carrier.(opentracing.TextMapWriter); ok {
 
-		textMapReaderWriter(sc.baggageItems).CloneTo(w)
+		textMapReaderWriter(sc.snapshot()).CloneTo(w)
 		return nil
 	}
 
@@ -394,9 +749,40 @@ func (t Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier i
 }
 
 // Extract returns a SpanContext given the format and the carrier.
-// The SpanContext returned represents the parent span (ie, SpanId refers to the parent span's own SpanId).
+// The SpanContext returned represents the parent span (ie, SpanId refers
+// to the parent span's own SpanId). If the Tracer has a shadow tracer
+// configured, the returned context also carries whatever SpanContext
+// the shadow tracer extracts from the same carrier, wrapped in a
+// ShadowContext.
 // TODO support all the BuiltinFormats
-func (t Tracer) Extract(format interface{}, carrier interface{}) (ctx opentracing.SpanContext, err error) {
+func (t Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	local, err := t.extractLocal(format, carrier)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.shadow == nil {
+		return local, nil
+	}
+
+	sc, ok := asSpanContext(local)
+	if !ok {
+		return local, nil
+	}
+
+	shadowCtx, shadowErr := t.shadow.Extract(format, carrier)
+	if shadowErr != nil {
+		// Veneur's own context is still usable even if the shadow
+		// tracer couldn't make sense of this carrier.
+		return sc, nil
+	}
+
+	return &ShadowContext{spanContext: sc, Shadow: shadowCtx}, nil
+}
+
+// extractLocal is Extract's original implementation, parsing only
+// veneur's own propagation formats.
+func (t Tracer) extractLocal(format interface{}, carrier interface{}) (ctx opentracing.SpanContext, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			// TODO annotate this error type
@@ -428,6 +814,87 @@ func (t Tracer) Extract(format interface{}, carrier interface{}) (ctx opentracin
 		return trace.context(), nil
 	}
 
+	if format == opentracing.HTTPHeaders {
+		tm, ok := carrier.(opentracing.TextMapReader)
+		if !ok {
+			return nil, opentracing.ErrInvalidCarrier
+		}
+
+		traceIdHeader := textMapReaderGet(tm, b3TraceIdHeader)
+		spanIdHeader := textMapReaderGet(tm, b3SpanIdHeader)
+		parentIdHeader := textMapReaderGet(tm, b3ParentSpanIdHeader)
+		sampledHeader := textMapReaderGet(tm, b3SampledHeader)
+		flagsHeader := textMapReaderGet(tm, b3FlagsHeader)
+
+		c := &spanContext{}
+		c.Init()
+
+		if flagsHeader == "1" {
+			// X-B3-Flags: 1 is the B3 "debug" flag: it forces sampling
+			// regardless of X-B3-Sampled.
+			c.baggageItems["debug"] = "1"
+			sampledHeader = "1"
+		}
+
+		if W3CPropagation {
+			if tp := textMapReaderGet(tm, w3cTraceParentHeader); tp != "" {
+				traceId, spanId, sampled, perr := parseW3CTraceParent(tp)
+				if perr == nil {
+					traceIdHeader = formatB3Id(traceId)
+					spanIdHeader = formatB3Id(spanId)
+					if sampled {
+						sampledHeader = "1"
+					} else {
+						sampledHeader = "0"
+					}
+				}
+			}
+			if ts := textMapReaderGet(tm, w3cTraceStateHeader); ts != "" {
+				c.baggageItems["tracestate"] = ts
+			}
+		}
+
+		if sampledHeader != "" {
+			c.baggageItems["sampled"] = sampledHeader
+		}
+
+		if traceIdHeader == "" && spanIdHeader == "" {
+			// No span identity was propagated -- this is a "debug" span,
+			// carrying only a sampling decision (eg just X-B3-Sampled or
+			// X-B3-Flags). Downstream middleware still needs a valid
+			// context to make sampling decisions off of.
+			if sampledHeader == "" && len(c.baggageItems) == 0 {
+				return nil, opentracing.ErrSpanContextNotFound
+			}
+			for k, v := range baggageFromHeaders(tm) {
+				c.baggageItems[k] = v
+			}
+			return c, nil
+		}
+
+		traceId, err := parseB3Id(traceIdHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", b3TraceIdHeader, err)
+		}
+		spanId, err := parseB3Id(spanIdHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", b3SpanIdHeader, err)
+		}
+		c.baggageItems["traceid"] = strconv.FormatInt(traceId, 10)
+		c.baggageItems["spanid"] = strconv.FormatInt(spanId, 10)
+		if parentIdHeader != "" {
+			if parentId, perr := parseB3Id(parentIdHeader); perr == nil {
+				c.baggageItems["parentid"] = strconv.FormatInt(parentId, 10)
+			}
+		}
+
+		for k, v := range baggageFromHeaders(tm) {
+			c.baggageItems[k] = v
+		}
+
+		return c, nil
+	}
+
 	if tm, ok := carrier.(opentracing.TextMapReader); ok {
 
 		// carrier is guaranteed to be an opentracing.TextMapReader by contract
@@ -452,6 +919,73 @@ func (t Tracer) Extract(format interface{}, carrier interface{}) (ctx opentracin
 	return nil, opentracing.ErrUnsupportedFormat
 }
 
+// formatB3Id hex-encodes a 64-bit trace/span ID, zero-padded to 16
+// characters, per the B3 propagation spec.
+func formatB3Id(id int64) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
+
+// parseB3Id accepts either the 16-character (64-bit) or 32-character
+// (128-bit) form of a B3 ID and returns its lower 64 bits, since
+// veneur's Trace only tracks 64-bit TraceId/SpanId fields.
+func parseB3Id(s string) (int64, error) {
+	if len(s) > 16 {
+		s = s[len(s)-16:]
+	}
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v), nil
+}
+
+// formatW3CTraceParent renders the W3C "traceparent" header for the
+// given spanContext, expanding the 64-bit trace ID out to the 128-bit
+// width the spec requires.
+func formatW3CTraceParent(sc *spanContext) string {
+	flags := "00"
+	if sc.Sampled() {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%032x-%016x-%s", uint64(sc.TraceId()), uint64(sc.SpanId()), flags)
+}
+
+// parseW3CTraceParent parses a W3C "traceparent" header value, returning
+// the lower 64 bits of the trace and span IDs and the sampled flag.
+func parseW3CTraceParent(value string) (traceId int64, spanId int64, sampled bool, err error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 {
+		return 0, 0, false, errors.New("traceparent: expected 4 dash-separated fields")
+	}
+	traceId, err = parseB3Id(parts[1])
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("traceparent: invalid trace-id: %s", err)
+	}
+	spanId, err = parseB3Id(parts[2])
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("traceparent: invalid parent-id: %s", err)
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("traceparent: invalid flags: %s", err)
+	}
+	return traceId, spanId, flags&0x1 == 1, nil
+}
+
+// baggageFromHeaders scans a TextMapReader for Ot-Baggage- prefixed
+// keys and returns the baggage items they carry, with the prefix
+// stripped.
+func baggageFromHeaders(tm opentracing.TextMapReader) map[string]string {
+	baggage := map[string]string{}
+	tm.ForeachKey(func(k, v string) error {
+		if len(k) > len(baggagePrefix) && strings.EqualFold(k[:len(baggagePrefix)], baggagePrefix) {
+			baggage[k[len(baggagePrefix):]] = v
+		}
+		return nil
+	})
+	return baggage
+}
+
 func textMapReaderGet(tmr opentracing.TextMapReader, key string) (value string) {
 	tmr.ForeachKey(func(k, v string) error {
 		if strings.ToLower(key) == strings.ToLower(k) {
@@ -0,0 +1,79 @@
+package trace
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// newTestSpan builds a minimally-populated, sampled Span for unit
+// tests, bypassing StartTrace/Tracer.StartSpan so these tests don't
+// depend on the rest of the (incomplete, in this chunk) package.
+func newTestSpan() *Span {
+	return &Span{
+		Trace:   &Trace{TraceId: 1, SpanId: 2, Resource: "test"},
+		tracer:  Tracer{},
+		sampled: true,
+		ctx:     newSpanContext(),
+	}
+}
+
+// TestSpanConcurrentAccess exercises SetTag, SetBaggageItem, and Inject
+// from many goroutines at once. Run with -race to catch data races in
+// Span and spanContext's locking.
+func TestSpanConcurrentAccess(t *testing.T) {
+	span := newTestSpan()
+	tracer := Tracer{}
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	wg.Add(goroutines * 3)
+
+	for i := 0; i < goroutines; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			span.SetTag("tag-"+strconv.Itoa(i), i)
+		}()
+		go func() {
+			defer wg.Done()
+			span.SetBaggageItem("item-"+strconv.Itoa(i), strconv.Itoa(i))
+		}()
+		go func() {
+			defer wg.Done()
+			carrier := opentracing.TextMapCarrier{}
+			if err := tracer.Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+				t.Errorf("Inject: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestSpanSetBaggageItemPersists is a regression test for a bug where
+// SetBaggageItem wrote into a freshly constructed spanContext that
+// contextAsParent immediately discarded, so the baggage item it set
+// never actually stuck.
+func TestSpanSetBaggageItemPersists(t *testing.T) {
+	span := newTestSpan()
+	span.SetBaggageItem("foo", "bar")
+
+	if got := span.BaggageItem("foo"); got != "bar" {
+		t.Fatalf("BaggageItem(%q) = %q, want %q", "foo", got, "bar")
+	}
+
+	var sawFoo string
+	span.Context().ForeachBaggageItem(func(k, v string) bool {
+		if k == "foo" {
+			sawFoo = v
+			return false
+		}
+		return true
+	})
+	if sawFoo != "bar" {
+		t.Fatalf("baggage item %q did not survive into Context(): got %q", "foo", sawFoo)
+	}
+}
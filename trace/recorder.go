@@ -0,0 +1,138 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SpanRecorder is implemented by backends that accept finished spans.
+// Tracer.recorder, if set, has RecordSpan called on it once per span,
+// from Span.FinishWithOptions.
+type SpanRecorder interface {
+	RecordSpan(t *Trace) error
+}
+
+// UDPRecorder sends finished spans to veneur's local trace socket, using
+// the same ssf.SSFSample wire format as Tracer.Inject's Binary carrier.
+type UDPRecorder struct {
+	conn net.Conn
+}
+
+// NewUDPRecorder dials addr (veneur's trace listener, eg "127.0.0.1:8128")
+// over UDP and returns a SpanRecorder that forwards spans to it.
+func NewUDPRecorder(addr string) (*UDPRecorder, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &UDPRecorder{conn: conn}, nil
+}
+
+// RecordSpan marshals t as an ssf.SSFSample protobuf and sends it to the
+// configured trace socket.
+func (r *UDPRecorder) RecordSpan(t *Trace) error {
+	return t.ProtoMarshalTo(r.conn)
+}
+
+// Close releases the recorder's underlying UDP socket.
+func (r *UDPRecorder) Close() error {
+	return r.conn.Close()
+}
+
+// ZipkinHTTPRecorder POSTs finished spans to a Zipkin v2-compatible HTTP
+// collector, eg Zipkin's own /api/v2/spans or a compatible ingest.
+type ZipkinHTTPRecorder struct {
+	CollectorURL string
+	// ServiceName is reported as the span's localEndpoint.serviceName.
+	// It defaults to the package-level Service var.
+	ServiceName string
+	Client      *http.Client
+}
+
+// NewZipkinHTTPRecorder returns a ZipkinHTTPRecorder that posts spans to
+// collectorURL (eg "http://localhost:9411/api/v2/spans").
+func NewZipkinHTTPRecorder(collectorURL string) *ZipkinHTTPRecorder {
+	return &ZipkinHTTPRecorder{
+		CollectorURL: collectorURL,
+		ServiceName:  Service,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+type zipkinSpan struct {
+	TraceId       string             `json:"traceId"`
+	Id            string             `json:"id"`
+	ParentId      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name"`
+	Timestamp     int64              `json:"timestamp"`
+	Duration      int64              `json:"duration"`
+	LocalEndpoint zipkinEndpoint     `json:"localEndpoint"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+}
+
+// RecordSpan translates t into a Zipkin v2 span -- Resource becomes
+// name, Tags become tags, and any tag logFieldToTag produced (see
+// logFieldTagPrefix) becomes an annotation instead -- and POSTs it to
+// CollectorURL.
+func (r *ZipkinHTTPRecorder) RecordSpan(t *Trace) error {
+	serviceName := r.ServiceName
+	if serviceName == "" {
+		serviceName = Service
+	}
+
+	span := zipkinSpan{
+		TraceId:       formatB3Id(t.TraceId),
+		Id:            formatB3Id(t.SpanId),
+		Name:          t.Resource,
+		Timestamp:     t.Start.UnixNano() / int64(time.Microsecond),
+		Duration:      t.Duration.Nanoseconds() / int64(time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{ServiceName: serviceName},
+		Tags:          map[string]string{},
+	}
+	if t.ParentId != 0 {
+		span.ParentId = formatB3Id(t.ParentId)
+	}
+
+	finish := t.Start.Add(t.Duration)
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(tag.Name, logFieldTagPrefix) {
+			key := strings.TrimPrefix(tag.Name, logFieldTagPrefix)
+			span.Annotations = append(span.Annotations, zipkinAnnotation{
+				Timestamp: finish.UnixNano() / int64(time.Microsecond),
+				Value:     fmt.Sprintf("%s=%s", key, tag.Value),
+			})
+			continue
+		}
+		span.Tags[tag.Name] = tag.Value
+	}
+
+	body, err := json.Marshal([]zipkinSpan{span})
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.Client.Post(r.CollectorURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("zipkin collector %s returned status %d", r.CollectorURL, resp.StatusCode)
+	}
+	return nil
+}
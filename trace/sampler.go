@@ -0,0 +1,148 @@
+package trace
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether a trace should be sampled. IsSampled is called
+// once per root span, at StartSpan time, with the trace's freshly
+// assigned TraceId; the tags it returns are attached to the root span so
+// the sampling decision is visible downstream (per Jaeger's
+// sampler.type/sampler.param convention).
+type Sampler interface {
+	IsSampled(traceId int64, operationName string) (sampled bool, tags map[string]string)
+}
+
+// ConstSampler samples every trace the same way. It's mostly useful for
+// tests and for explicitly disabling sampling (ConstSampler(true)).
+type ConstSampler bool
+
+// IsSampled always returns the constant decision the sampler was created with.
+func (s ConstSampler) IsSampled(traceId int64, operationName string) (bool, map[string]string) {
+	return bool(s), map[string]string{
+		"sampler.type":  "const",
+		"sampler.param": strconv.FormatBool(bool(s)),
+	}
+}
+
+// ProbabilisticSampler samples a fixed proportion of traces. The
+// decision is a deterministic function of the trace ID, so it survives
+// propagation to downstream services without needing to travel as a
+// separate flag.
+type ProbabilisticSampler struct {
+	rate      float64
+	threshold uint64
+}
+
+// NewProbabilisticSampler returns a ProbabilisticSampler that samples
+// roughly the given fraction (0.0-1.0) of traces.
+func NewProbabilisticSampler(rate float64) *ProbabilisticSampler {
+	if rate < 0 {
+		rate = 0
+	} else if rate > 1 {
+		rate = 1
+	}
+	threshold := uint64(math.MaxUint64)
+	if rate < 1 {
+		// float64(math.MaxUint64) rounds up to 2^64, so multiplying by
+		// it and converting back to uint64 overflows right at rate=1
+		// (threshold silently wraps to 2^63, ie. rate=0.5). Only scale
+		// when rate is strictly below 1; rate>=1 always samples.
+		threshold = uint64(rate * float64(math.MaxUint64))
+	}
+	return &ProbabilisticSampler{
+		rate:      rate,
+		threshold: threshold,
+	}
+}
+
+// IsSampled reports traceId as sampled iff uint64(traceId) falls below
+// the sampler's rate-scaled threshold; at rate 1 every trace is sampled.
+func (s *ProbabilisticSampler) IsSampled(traceId int64, operationName string) (bool, map[string]string) {
+	tags := map[string]string{
+		"sampler.type":  "probabilistic",
+		"sampler.param": strconv.FormatFloat(s.rate, 'f', -1, 64),
+	}
+	if s.rate >= 1 {
+		return true, tags
+	}
+	return uint64(traceId) < s.threshold, tags
+}
+
+// RateLimitingSampler samples at most maxTracesPerSecond traces per
+// second, using a leaky bucket of tokens refilled on a ticker.
+type RateLimitingSampler struct {
+	maxTracesPerSecond float64
+	maxTokens          int64
+	tokens             int64
+	stop               chan struct{}
+}
+
+// NewRateLimitingSampler starts a RateLimitingSampler that admits at
+// most maxTracesPerSecond traces per second. Callers should Close it
+// once it's no longer needed, to stop its background refill goroutine.
+// A maxTracesPerSecond of zero or less admits one trace per second.
+func NewRateLimitingSampler(maxTracesPerSecond float64) *RateLimitingSampler {
+	maxTokens := int64(maxTracesPerSecond)
+	if maxTokens < 1 {
+		maxTokens = 1
+	}
+	s := &RateLimitingSampler{
+		maxTracesPerSecond: maxTracesPerSecond,
+		maxTokens:          maxTokens,
+		tokens:             maxTokens,
+		stop:               make(chan struct{}),
+	}
+	go s.refill()
+	return s
+}
+
+func (s *RateLimitingSampler) refill() {
+	interval := time.Second / time.Duration(s.maxTokens)
+	if s.maxTracesPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / s.maxTracesPerSecond)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for {
+				tokens := atomic.LoadInt64(&s.tokens)
+				if tokens >= s.maxTokens {
+					break
+				}
+				if atomic.CompareAndSwapInt64(&s.tokens, tokens, tokens+1) {
+					break
+				}
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// IsSampled consumes a token if one is available, and reports whether it could.
+func (s *RateLimitingSampler) IsSampled(traceId int64, operationName string) (bool, map[string]string) {
+	tags := map[string]string{
+		"sampler.type":  "ratelimiting",
+		"sampler.param": strconv.FormatFloat(s.maxTracesPerSecond, 'f', -1, 64),
+	}
+	for {
+		tokens := atomic.LoadInt64(&s.tokens)
+		if tokens <= 0 {
+			return false, tags
+		}
+		if atomic.CompareAndSwapInt64(&s.tokens, tokens, tokens-1) {
+			return true, tags
+		}
+	}
+}
+
+// Close stops the sampler's background refill goroutine.
+func (s *RateLimitingSampler) Close() {
+	close(s.stop)
+}
@@ -0,0 +1,40 @@
+package trace
+
+import (
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+var _ opentracing.SpanContext = &ShadowContext{}
+
+// ShadowContext wraps veneur's own spanContext together with the
+// SpanContext of a mirrored shadow tracer (Zipkin, Jaeger, Lightstep,
+// ...), so a single Inject/Extract call can carry both sets of headers
+// on one carrier, while SpanReference lookups during StartSpan still
+// resolve against veneur's own spanContext.
+type ShadowContext struct {
+	*spanContext
+	Shadow opentracing.SpanContext
+}
+
+// asSpanContext unwraps sc to veneur's own concrete spanContext type,
+// whether it's a bare *spanContext or one embedded in a ShadowContext.
+func asSpanContext(sc opentracing.SpanContext) (*spanContext, bool) {
+	switch c := sc.(type) {
+	case *spanContext:
+		return c, true
+	case *ShadowContext:
+		return c.spanContext, true
+	default:
+		return nil, false
+	}
+}
+
+// SetShadowTracer attaches a secondary opentracing.Tracer that every
+// StartSpan/Finish/Inject/Extract call is mirrored to, so veneur's
+// native SSF pipeline and an external backend both receive identical
+// data during a migration. It must be called before any spans are
+// started, since StartSpan copies the Tracer's fields onto each Span it
+// creates.
+func (t *Tracer) SetShadowTracer(shadow opentracing.Tracer) {
+	t.shadow = shadow
+}